@@ -0,0 +1,172 @@
+package cleanuphttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCleanup table-drives the behaviors that make cleanup/runRoutine the
+// riskiest code in the package: nested pushes during unwind, error
+// aggregation, panic recovery, and the per-routine timeout split.
+func TestCleanup(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{"nested push ordering", testNestedPushOrdering},
+		{"error aggregation", testErrorAggregation},
+		{"panic does not abort stack", testPanicDoesNotAbortStack},
+		{"per-routine timeout split", testPerRoutineTimeoutSplit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+// testNestedPushOrdering verifies that a routine pushed from within another
+// routine's execution runs before the stack continues unwinding to
+// routines that were already below it, mirroring testing.T.Cleanup.
+func testNestedPushOrdering(t *testing.T) {
+	c := &CleanupHTTP{}
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	c.PreCleanupPushFunc(func(context.Context) error {
+		record("a")
+		return nil
+	})
+	c.PreCleanupPushFunc(func(context.Context) error {
+		record("b")
+		c.PreCleanupPushFunc(func(context.Context) error {
+			record("c")
+			return nil
+		})
+		return nil
+	})
+
+	if err := c.preCleanup(context.Background()); err != nil {
+		t.Fatalf("preCleanup returned unexpected error: %v", err)
+	}
+
+	want := []string{"b", "c", "a"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+// testErrorAggregation verifies that errors from multiple failing routines
+// are all preserved in the aggregate returned by cleanup.
+func testErrorAggregation(t *testing.T) {
+	c := &CleanupHTTP{}
+
+	err1 := errors.New("routine 1 failed")
+	err2 := errors.New("routine 2 failed")
+
+	c.PostCleanupPushFunc(func(context.Context) error { return err1 })
+	c.PostCleanupPushFunc(func(context.Context) error { return nil })
+	c.PostCleanupPushFunc(func(context.Context) error { return err2 })
+
+	err := c.postCleanup(context.Background())
+	if err == nil {
+		t.Fatal("postCleanup returned nil, want aggregated error")
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("aggregated error does not wrap err1: %v", err)
+	}
+	if !errors.Is(err, err2) {
+		t.Errorf("aggregated error does not wrap err2: %v", err)
+	}
+}
+
+// testPanicDoesNotAbortStack verifies that a panic in one routine is
+// recovered into an error and does not prevent the rest of the stack from
+// running.
+func testPanicDoesNotAbortStack(t *testing.T) {
+	c := &CleanupHTTP{}
+
+	var ran bool
+
+	c.PreCleanupPushFunc(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	c.PreCleanupPushFunc(func(context.Context) error {
+		panic("boom")
+	})
+
+	err := c.preCleanup(context.Background())
+	if err == nil {
+		t.Fatal("preCleanup returned nil, want error recovered from panic")
+	}
+	if !ran {
+		t.Error("routine below the panicking one did not run")
+	}
+}
+
+// testPerRoutineTimeoutSplit verifies that runRoutine carves each routine's
+// context out of the overall deadline in proportion to how many routines
+// are still left on the stack.
+func testPerRoutineTimeoutSplit(t *testing.T) {
+	c := &CleanupHTTP{}
+
+	const n = 3
+	deadlines := make([]time.Time, 0, n)
+	var mu sync.Mutex
+
+	for i := 0; i < n; i++ {
+		c.PreCleanupPushFunc(func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				return fmt.Errorf("routine got no deadline")
+			}
+			mu.Lock()
+			deadlines = append(deadlines, deadline)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	const total = 900 * time.Millisecond
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), total)
+	defer cancel()
+
+	if err := c.preCleanup(ctx); err != nil {
+		t.Fatalf("preCleanup returned unexpected error: %v", err)
+	}
+
+	if len(deadlines) != n {
+		t.Fatalf("got %d recorded deadlines, want %d", len(deadlines), n)
+	}
+
+	overallDeadline, _ := ctx.Deadline()
+	for i, d := range deadlines {
+		if d.After(overallDeadline) {
+			t.Errorf("routine %d deadline %v is after the overall deadline %v", i, d, overallDeadline)
+		}
+		// The first routine to run sees the whole stack still ahead of
+		// it, so it should get roughly total/n of the budget, well
+		// short of the full remaining time.
+		if i == 0 && !d.Before(start.Add(total*3/4)) {
+			t.Errorf("first routine's deadline %v was not carved out of the overall %v budget (start %v)", d, total, start)
+		}
+	}
+}
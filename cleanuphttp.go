@@ -2,7 +2,9 @@ package cleanuphttp
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,123 +19,494 @@ var DefaultCleanupHTTP = &defaultCleanupHTTP
 
 var defaultCleanupHTTP CleanupHTTP
 
-// Routine is cleanup function type.
+// Routine is the legacy cleanup function type. It cannot report failure or
+// observe the shutdown deadline; prefer CleanupFunc in new code.
 type Routine func(interface{})
 
+// CleanupFunc is a cleanup function that receives a context carrying the
+// remaining shutdown deadline and may report failure. A CleanupFunc may
+// itself call PreCleanupPushFunc/PostCleanupPushFunc (or their Routine
+// equivalents) on the same CleanupHTTP; the newly pushed routines run
+// before the stack continues to unwind, mirroring the nested-cleanup
+// semantics of testing.T.Cleanup. A panic inside a CleanupFunc is
+// recovered and reported as an error so it cannot abort the rest of the
+// stack.
+type CleanupFunc func(ctx context.Context) error
+
 // CleanupHTTP runs clean-up routines before/after the server is shut down.
 type CleanupHTTP struct {
 	Server *http.Server
 
-	preRoutines  routineStack
-	postRoutines routineStack
-	closing      int32
-	interrupted  bool
+	// Logger receives Serve's diagnostic output. If nil, the Logger set
+	// via SetDefaultLogger is used, which itself defaults to the stdlib
+	// log package.
+	Logger Logger
+
+	// NoSignalHandling disables the built-in SIGINT/SIGTERM handling, so
+	// Serve, ServeListener, and ServeTLS only stop when the embedder
+	// calls Shutdown, typically from its own signal loop.
+	NoSignalHandling bool
+
+	// Signals is the set of signals that trigger shutdown. If empty, it
+	// defaults to SIGINT and SIGTERM. Ignored when NoSignalHandling is
+	// true.
+	Signals []os.Signal
+
+	// Reload, if set, is called whenever SIGHUP is received, instead of
+	// SIGHUP triggering shutdown. Once a shutdown signal has been handled,
+	// SIGHUP is no longer relayed and Reload is not called again.
+	Reload func()
+
+	// ForceShutdownAfter, if positive, hard-exits the process via
+	// os.Exit(1) if cleanup and server shutdown haven't finished within
+	// this long of a shutdown signal, so a stuck cleanup routine can't
+	// wedge the process forever.
+	ForceShutdownAfter time.Duration
+
+	// BeforeShutdown, if set, runs synchronously as soon as a shutdown is
+	// triggered (by a signal, or by a call to Shutdown), before idle
+	// connections are force-closed and before any pre-cleanup routine
+	// runs.
+	BeforeShutdown func()
+
+	// ShutdownInitiated, if set, runs after pre-cleanup routines have
+	// run, immediately before Server.Shutdown (and that of every server
+	// registered via AddServer) is called.
+	ShutdownInitiated func()
+
+	// ShutdownComplete, if set, runs once every server has finished
+	// shutting down and post-cleanup routines have run, at the very end
+	// of Serve/ServeListener/ServeTLS.
+	ShutdownComplete func()
+
+	// AdminAddr, if non-empty, makes Serve, ServeListener, and ServeTLS
+	// also listen on this address, serving /healthz, /readyz, and
+	// /metrics from Handler. It shares the same lifecycle as Server,
+	// registered the same way as AddServer would.
+	AdminAddr string
+
+	// PreShutdownDelay, if positive, is how long Serve waits after
+	// /readyz starts failing before it force-closes idle connections and
+	// calls Server.Shutdown, giving a load balancer or k8s endpoints
+	// controller time to notice and stop routing new traffic.
+	PreShutdownDelay time.Duration
+
+	preRoutines     routineStack
+	postRoutines    routineStack
+	closing         int32
+	unwinding       int32
+	ready           int32
+	interrupted     bool
+	readinessChecks []readinessCheck
+
+	mu           sync.Mutex
+	quit         chan struct{}
+	extraServers []extraServer
+	started      bool
+	conns        *connTracker
 }
 
-// Serve runs the http server with clean-up routines.
-func (c *CleanupHTTP) Serve(timeout time.Duration) {
-	quit := make(chan struct{})
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+// extraServer is an additional http.Server registered via AddServer. It is
+// served and shut down alongside Server, under the same cleanup lifecycle.
+type extraServer struct {
+	server   *http.Server
+	listener net.Listener
+}
 
-	go c.handleSignal(interrupt, quit)
+// serveExtra serves e.server on e.listener, upgrading to ServeTLS when
+// e.server.TLSConfig is set, the same way ServeListener does for Server, so
+// a server registered via AddServer for HTTP/2 or gRPC doesn't silently
+// fall back to serving HTTP/1 over its TLS listener.
+func serveExtra(e extraServer) error {
+	if e.server.TLSConfig != nil {
+		return e.server.ServeTLS(e.listener, "", "")
+	}
+	return e.server.Serve(e.listener)
+}
 
-	go func() {
-		if err := c.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("ListenAndServe failed: %s", err.Error())
+// AddServer registers an additional http.Server to be served on l and shut
+// down alongside Server, sharing the same ordered pre/post cleanup
+// lifecycle. Use it to multiplex several protocols (HTTP/1, HTTP/2, gRPC)
+// off different listeners behind a connection matcher such as
+// soheilhy/cmux, while still getting a single coordinated shutdown. If
+// server.TLSConfig is set, it is served via ServeTLS so HTTP/2 is
+// negotiated automatically, the same way ServeListener behaves for Server.
+//
+// AddServer must be called before Serve, ServeListener, or ServeTLS: those
+// take a one-time snapshot of the registered servers when they start, so a
+// server registered afterward would never be served, tracked, or shut
+// down. Calling it too late returns an error instead of silently dropping
+// the registration.
+func (c *CleanupHTTP) AddServer(server *http.Server, l net.Listener) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.started {
+		return fmt.Errorf("cleanuphttp: AddServer called after Serve/ServeListener/ServeTLS already started; register servers before calling one of those")
+	}
+
+	c.extraServers = append(c.extraServers, extraServer{server: server, listener: l})
+	return nil
+}
+
+// Serve runs the http server with clean-up routines. It returns the
+// aggregated error, if any, from the cleanup routines and from shutting
+// down every server.
+func (c *CleanupHTTP) Serve(timeout time.Duration) error {
+	return c.serve(timeout, c.Server.ListenAndServe)
+}
+
+// ServeListener runs the http server on the given listener with clean-up
+// routines. It allows serving on a pre-bound net.Listener, an existing TLS
+// listener, a Unix socket, or a matched connection from a multiplexer such
+// as soheilhy/cmux. If Server.TLSConfig is set, HTTP/2 is configured
+// automatically, the same way ListenAndServeTLS does for a plain TLS
+// listener.
+func (c *CleanupHTTP) ServeListener(l net.Listener, timeout time.Duration) error {
+	return c.serve(timeout, func() error {
+		if c.Server.TLSConfig != nil {
+			return c.Server.ServeTLS(l, "", "")
+		}
+		return c.Server.Serve(l)
+	})
+}
+
+// ServeTLS runs the http server with TLS on Server.Addr with clean-up
+// routines.
+func (c *CleanupHTTP) ServeTLS(certFile, keyFile string, timeout time.Duration) error {
+	return c.serve(timeout, func() error {
+		return c.Server.ListenAndServeTLS(certFile, keyFile)
+	})
+}
+
+func (c *CleanupHTTP) serve(timeout time.Duration, listenAndServe func() error) error {
+	quit := c.quitChan()
+
+	if !c.NoSignalHandling {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, c.signals()...)
+		if c.Reload != nil {
+			signal.Notify(interrupt, syscall.SIGHUP)
+		}
+
+		go c.handleSignal(interrupt, quit)
+	}
+
+	stop := func(name string, err error) {
+		if err != nil && err != http.ErrServerClosed {
+			c.logger().Errorf("%s failed: %s", name, err.Error())
 		}
 
 		if atomic.CompareAndSwapInt32(&c.closing, 0, 1) {
 			close(quit)
 		}
+	}
+
+	if c.AdminAddr != "" {
+		if err := c.addAdminServer(); err != nil {
+			c.logger().Errorf("admin listener failed: %s", err.Error())
+		}
+	}
+
+	conns := c.connTracker()
+	conns.track(c.Server)
+
+	go func() {
+		stop("ListenAndServe", listenAndServe())
 	}()
 
+	c.mu.Lock()
+	extra := append([]extraServer(nil), c.extraServers...)
+	c.started = true
+	c.mu.Unlock()
+
+	for _, e := range extra {
+		e := e
+		conns.track(e.server)
+		go func() {
+			stop("Serve", serveExtra(e))
+		}()
+	}
+
+	c.setReady(true)
+
 	<-quit
-	c.preCleanup()
-	defer c.postCleanup()
 
-	var (
-		ctx    context.Context
-		cancel context.CancelFunc
-	)
+	c.setReady(false)
+
+	if c.ForceShutdownAfter > 0 {
+		timer := time.AfterFunc(c.ForceShutdownAfter, func() {
+			c.logger().Errorf("cleanup exceeded ForceShutdownAfter (%s), forcing exit", c.ForceShutdownAfter)
+			os.Exit(1)
+		})
+		defer timer.Stop()
+	}
+
+	if c.BeforeShutdown != nil {
+		c.BeforeShutdown()
+	}
 
+	if c.PreShutdownDelay > 0 {
+		time.Sleep(c.PreShutdownDelay)
+	}
+
+	conns.closeIdle()
+
+	ctx, cancel := c.shutdownContext(timeout)
+	defer cancel()
+
+	preErr := c.preCleanup(ctx)
+
+	if c.ShutdownInitiated != nil {
+		c.ShutdownInitiated()
+	}
+
+	shutdownErr := c.shutdownServers(ctx, extra)
+	postErr := c.postCleanup(ctx)
+
+	if c.ShutdownComplete != nil {
+		c.ShutdownComplete()
+	}
+
+	return errors.Join(preErr, shutdownErr, postErr)
+}
+
+func (c *CleanupHTTP) shutdownContext(timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(
-			context.Background(),
-			timeout,
-		)
-		defer cancel()
-	} else {
-		ctx = context.Background()
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// Shutdown programmatically triggers the same shutdown sequence that
+// Serve, ServeListener, and ServeTLS run when they receive SIGINT/SIGTERM,
+// letting an embedder with NoSignalHandling set drive shutdown from its
+// own signal loop, or trigger it for any other reason.
+func (c *CleanupHTTP) Shutdown() {
+	quit := c.quitChan()
+	if atomic.CompareAndSwapInt32(&c.closing, 0, 1) {
+		close(quit)
+	}
+}
+
+func (c *CleanupHTTP) quitChan() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.quit == nil {
+		c.quit = make(chan struct{})
+	}
+	return c.quit
+}
+
+func (c *CleanupHTTP) connTracker() *connTracker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conns == nil {
+		c.conns = newConnTracker()
+	}
+	return c.conns
+}
+
+// addAdminServer registers a server for Handler on AdminAddr via
+// AddServer, so it shares the same lifecycle as Server.
+func (c *CleanupHTTP) addAdminServer() error {
+	l, err := net.Listen("tcp", c.AdminAddr)
+	if err != nil {
+		return err
+	}
+
+	return c.AddServer(&http.Server{Handler: c.Handler()}, l)
+}
+
+func (c *CleanupHTTP) isReady() bool {
+	return atomic.LoadInt32(&c.ready) != 0
+}
+
+func (c *CleanupHTTP) setReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&c.ready, v)
+}
+
+// shutdownServers calls Shutdown on Server and every server registered via
+// AddServer concurrently, under the same ctx, and returns their aggregated
+// error.
+func (c *CleanupHTTP) shutdownServers(ctx context.Context, extra []extraServer) error {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	record := func(err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
 	}
 
-	if err := c.Server.Shutdown(ctx); err != nil {
-		log.Printf("Shutdown failed: %s", err.Error())
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		record(c.Server.Shutdown(ctx))
+	}()
+
+	for _, e := range extra {
+		e := e
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record(e.server.Shutdown(ctx))
+		}()
 	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
 // PreCleanupPush pushes routine that runs before the given server is shut down
 // onto the top of the stack of clean-up handlers.
 // When routine is later invoked, it will be given arg as its argument.
 func (c *CleanupHTTP) PreCleanupPush(routine Routine, arg interface{}) {
-	c.cleanupPush(&c.preRoutines, routine, arg)
+	c.cleanupPush(&c.preRoutines, cleanupRoutine{
+		fn:      legacyCleanupFunc(routine, arg),
+		routine: routine,
+		arg:     arg,
+	})
 }
 
 // PreCleanupPop removes the routine at the top of the stack
 // of clean-up handlers for the given server.
 func (c *CleanupHTTP) PreCleanupPop() (Routine, interface{}, bool) {
-	return c.cleanupPop(&c.preRoutines)
+	r, ok := c.cleanupPop(&c.preRoutines)
+	if !ok {
+		return nil, nil, false
+	}
+	return r.routine, r.arg, true
+}
+
+// PreCleanupPushFunc pushes a CleanupFunc that runs before the given
+// server is shut down onto the top of the stack of clean-up handlers.
+func (c *CleanupHTTP) PreCleanupPushFunc(fn CleanupFunc) {
+	c.cleanupPush(&c.preRoutines, cleanupRoutine{fn: fn})
+}
+
+// PreCleanupPopFunc removes the CleanupFunc at the top of the stack of
+// clean-up handlers for the given server.
+func (c *CleanupHTTP) PreCleanupPopFunc() (CleanupFunc, bool) {
+	r, ok := c.cleanupPop(&c.preRoutines)
+	if !ok {
+		return nil, false
+	}
+	return r.fn, true
 }
 
 // PostCleanupPush pushes routine that runs after the given server is shut down
 // onto the top of the stack of clean-up handlers.
 // When routine is later invoked, it will be given arg as its argument.
 func (c *CleanupHTTP) PostCleanupPush(routine Routine, arg interface{}) {
-	c.cleanupPush(&c.postRoutines, routine, arg)
+	c.cleanupPush(&c.postRoutines, cleanupRoutine{
+		fn:      legacyCleanupFunc(routine, arg),
+		routine: routine,
+		arg:     arg,
+	})
 }
 
 // PostCleanupPop removes the routine at the top of the stack
 // of clean-up handlers for the given server.
 func (c *CleanupHTTP) PostCleanupPop() (Routine, interface{}, bool) {
-	return c.cleanupPop(&c.postRoutines)
+	r, ok := c.cleanupPop(&c.postRoutines)
+	if !ok {
+		return nil, nil, false
+	}
+	return r.routine, r.arg, true
 }
 
-func (c *CleanupHTTP) cleanupPush(stack *routineStack, routine Routine, arg interface{}) {
-	if c.isClosed() {
-		log.Println("Push failed: closed")
-		return
+// PostCleanupPushFunc pushes a CleanupFunc that runs after the given
+// server is shut down onto the top of the stack of clean-up handlers.
+func (c *CleanupHTTP) PostCleanupPushFunc(fn CleanupFunc) {
+	c.cleanupPush(&c.postRoutines, cleanupRoutine{fn: fn})
+}
+
+// PostCleanupPopFunc removes the CleanupFunc at the top of the stack of
+// clean-up handlers for the given server.
+func (c *CleanupHTTP) PostCleanupPopFunc() (CleanupFunc, bool) {
+	r, ok := c.cleanupPop(&c.postRoutines)
+	if !ok {
+		return nil, false
 	}
+	return r.fn, true
+}
 
-	r := cleanupRoutine{
-		routine: routine,
-		arg:     arg,
+// legacyCleanupFunc adapts a Routine/arg pair to a CleanupFunc so both
+// APIs are run the same way by cleanup.
+func legacyCleanupFunc(routine Routine, arg interface{}) CleanupFunc {
+	return func(context.Context) error {
+		routine(arg)
+		return nil
 	}
-	stack.push(r)
 }
 
-func (c *CleanupHTTP) cleanupPop(stack *routineStack) (Routine, interface{}, bool) {
-	if c.isClosed() {
-		log.Println("Pop failed: closed")
-		return nil, nil, false
+// cleanupPush pushes r onto stack, unless the CleanupHTTP is closed. A
+// push from within a routine that stack is currently unwinding is always
+// allowed, so nested PreCleanupPush/PostCleanupPush calls work.
+func (c *CleanupHTTP) cleanupPush(stack *routineStack, r cleanupRoutine) {
+	if c.isClosed() && !c.isUnwinding() {
+		c.logger().Warnf("Push failed: closed")
+		return
 	}
 
-	if r, ok := stack.pop(); ok {
-		routine := r.routine
-		arg := r.arg
-		return routine, arg, true
+	stack.push(r)
+}
+
+func (c *CleanupHTTP) cleanupPop(stack *routineStack) (cleanupRoutine, bool) {
+	if c.isClosed() && !c.isUnwinding() {
+		c.logger().Warnf("Pop failed: closed")
+		return cleanupRoutine{}, false
 	}
 
-	return nil, nil, false
+	return stack.pop()
+}
+
+// signals returns the configured shutdown signal set, defaulting to
+// SIGINT and SIGTERM.
+func (c *CleanupHTTP) signals() []os.Signal {
+	if len(c.Signals) > 0 {
+		return c.Signals
+	}
+	return []os.Signal{syscall.SIGINT, syscall.SIGTERM}
 }
 
 func (c *CleanupHTTP) handleSignal(interrupt chan os.Signal, quit chan struct{}) {
 	for i := range interrupt {
-		log.Printf("System call: %+v", i)
+		c.logger().Infof("System call: %+v", i)
+
+		if i == syscall.SIGHUP && c.Reload != nil {
+			c.Reload()
+			continue
+		}
+
 		if c.interrupted {
 			continue
 		}
 		c.interrupted = true
 
+		// Stop relaying signals to this channel, SIGHUP included. Unlike
+		// signal.Reset, this only affects this instance: if some other
+		// signal.Notify in the embedding process is also watching
+		// SIGINT/SIGTERM, that registration is left alone. If this was
+		// the only one, the runtime falls back to the default
+		// disposition (typically terminate) for it, so a second signal
+		// lets an operator force-exit a stuck cleanup routine.
+		signal.Stop(interrupt)
+
 		if c.isClosed() {
 			continue
 		}
@@ -144,63 +517,155 @@ func (c *CleanupHTTP) handleSignal(interrupt chan os.Signal, quit chan struct{})
 	}
 }
 
-func (c *CleanupHTTP) preCleanup() {
-	c.cleanup(&c.preRoutines)
+func (c *CleanupHTTP) preCleanup(ctx context.Context) error {
+	return c.cleanup(ctx, &c.preRoutines)
 }
 
-func (c *CleanupHTTP) postCleanup() {
-	c.cleanup(&c.postRoutines)
+func (c *CleanupHTTP) postCleanup(ctx context.Context) error {
+	return c.cleanup(ctx, &c.postRoutines)
 }
 
-func (c *CleanupHTTP) cleanup(stack *routineStack) {
+// cleanup pops and runs every routine on stack, including routines pushed
+// by a routine while it runs, so nested pushes run before the stack
+// continues to unwind. It aggregates every routine's error via
+// errors.Join.
+func (c *CleanupHTTP) cleanup(ctx context.Context, stack *routineStack) error {
+	atomic.StoreInt32(&c.unwinding, 1)
+	defer atomic.StoreInt32(&c.unwinding, 0)
+
+	var errs []error
 	for {
-		if r, ok := stack.pop(); ok {
-			routine := r.routine
-			arg := r.arg
-			routine(arg)
-		} else {
+		r, ok := stack.pop()
+		if !ok {
 			break
 		}
+
+		if err := c.runRoutine(ctx, stack, r); err != nil {
+			errs = append(errs, err)
+		}
 	}
+
+	return errors.Join(errs...)
+}
+
+// runRoutine runs r under a per-routine timeout budget carved out of
+// ctx's overall shutdown deadline (split evenly across r and whatever is
+// still left on stack), recovering a panic into an error.
+func (c *CleanupHTTP) runRoutine(ctx context.Context, stack *routineStack, r cleanupRoutine) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("cleanup routine panicked: %v", p)
+		}
+	}()
+
+	routineCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		if share := time.Until(deadline) / time.Duration(stack.len()+1); share > 0 {
+			var cancel context.CancelFunc
+			routineCtx, cancel = context.WithTimeout(ctx, share)
+			defer cancel()
+		}
+	}
+
+	return r.fn(routineCtx)
 }
 
 func (c *CleanupHTTP) isClosed() bool {
 	return atomic.LoadInt32(&c.closing) != 0
 }
 
+func (c *CleanupHTTP) isUnwinding() bool {
+	return atomic.LoadInt32(&c.unwinding) != 0
+}
+
 // Serve runs the given http server with cleanup routines.
-func Serve(server *http.Server, timeout time.Duration) {
+func Serve(server *http.Server, timeout time.Duration) error {
 	DefaultCleanupHTTP.Server = server
-	DefaultCleanupHTTP.Serve(timeout)
+	return DefaultCleanupHTTP.Serve(timeout)
+}
+
+// ServeListener runs the given http server on the given listener with
+// cleanup routines using the DefaultCleanupHTTP.
+func ServeListener(server *http.Server, l net.Listener, timeout time.Duration) error {
+	DefaultCleanupHTTP.Server = server
+	return DefaultCleanupHTTP.ServeListener(l, timeout)
+}
+
+// ServeTLS runs the given http server with TLS using the
+// DefaultCleanupHTTP.
+func ServeTLS(server *http.Server, certFile, keyFile string, timeout time.Duration) error {
+	DefaultCleanupHTTP.Server = server
+	return DefaultCleanupHTTP.ServeTLS(certFile, keyFile, timeout)
+}
+
+// AddServer registers an additional http.Server on the DefaultCleanupHTTP.
+// See CleanupHTTP.AddServer.
+func AddServer(server *http.Server, l net.Listener) error {
+	return DefaultCleanupHTTP.AddServer(server, l)
+}
+
+// Shutdown programmatically triggers a shutdown of the DefaultCleanupHTTP.
+// See CleanupHTTP.Shutdown.
+func Shutdown() {
+	DefaultCleanupHTTP.Shutdown()
 }
 
 // PreCleanupPush pushes routine that runs before a server is shut down
 // onto the top of the stack of clean-up handlers in the DefaultCleanupHTTP.
 // When routine is later invoked, it will be given arg as its argument.
 func PreCleanupPush(routine Routine, arg interface{}) {
-	DefaultCleanupHTTP.cleanupPush(&DefaultCleanupHTTP.preRoutines, routine, arg)
+	DefaultCleanupHTTP.PreCleanupPush(routine, arg)
 }
 
 // PreCleanupPop removes the routine at the top of the stack
 // of clean-up handlers in the DefaultCleanupHTTP.
 func PreCleanupPop() (Routine, interface{}, bool) {
-	return DefaultCleanupHTTP.cleanupPop(&DefaultCleanupHTTP.preRoutines)
+	return DefaultCleanupHTTP.PreCleanupPop()
+}
+
+// PreCleanupPushFunc pushes a CleanupFunc onto the top of the stack of
+// pre-shutdown clean-up handlers in the DefaultCleanupHTTP.
+func PreCleanupPushFunc(fn CleanupFunc) {
+	DefaultCleanupHTTP.PreCleanupPushFunc(fn)
+}
+
+// PreCleanupPopFunc removes the CleanupFunc at the top of the stack of
+// pre-shutdown clean-up handlers in the DefaultCleanupHTTP.
+func PreCleanupPopFunc() (CleanupFunc, bool) {
+	return DefaultCleanupHTTP.PreCleanupPopFunc()
 }
 
 // PostCleanupPush pushes routine that runs before a server is shut down
 // onto the top of the stack of clean-up handlers in the DefaultCleanupHTTP.
 // When routine is later invoked, it will be given arg as its argument.
 func PostCleanupPush(routine Routine, arg interface{}) {
-	DefaultCleanupHTTP.cleanupPush(&DefaultCleanupHTTP.postRoutines, routine, arg)
+	DefaultCleanupHTTP.PostCleanupPush(routine, arg)
 }
 
 // PostCleanupPop removes the routine at the top of the stack
 // of clean-up handlers in the DefaultCleanupHTTP.
 func PostCleanupPop() (Routine, interface{}, bool) {
-	return DefaultCleanupHTTP.cleanupPop(&DefaultCleanupHTTP.postRoutines)
+	return DefaultCleanupHTTP.PostCleanupPop()
+}
+
+// PostCleanupPushFunc pushes a CleanupFunc onto the top of the stack of
+// post-shutdown clean-up handlers in the DefaultCleanupHTTP.
+func PostCleanupPushFunc(fn CleanupFunc) {
+	DefaultCleanupHTTP.PostCleanupPushFunc(fn)
+}
+
+// PostCleanupPopFunc removes the CleanupFunc at the top of the stack of
+// post-shutdown clean-up handlers in the DefaultCleanupHTTP.
+func PostCleanupPopFunc() (CleanupFunc, bool) {
+	return DefaultCleanupHTTP.PostCleanupPopFunc()
 }
 
 type cleanupRoutine struct {
+	fn CleanupFunc
+
+	// routine and arg are set only when this entry was pushed through
+	// the legacy Routine-based API, so PreCleanupPop/PostCleanupPop can
+	// hand them back unchanged.
 	routine Routine
 	arg     interface{}
 }
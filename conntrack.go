@@ -0,0 +1,61 @@
+package cleanuphttp
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// connTracker records the state of a server's live connections via
+// http.Server.ConnState, so that idle keep-alive connections can be
+// force-closed as soon as shutdown begins instead of lingering until
+// Server.Shutdown's polling loop eventually notices them.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]http.ConnState
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: make(map[net.Conn]http.ConnState)}
+}
+
+// track installs a ConnState hook on server that records connection state,
+// chaining to any hook already set on server.
+func (t *connTracker) track(server *http.Server) {
+	next := server.ConnState
+	server.ConnState = func(conn net.Conn, state http.ConnState) {
+		t.mu.Lock()
+		switch state {
+		case http.StateClosed, http.StateHijacked:
+			delete(t.conns, conn)
+		default:
+			t.conns[conn] = state
+		}
+		t.mu.Unlock()
+
+		if next != nil {
+			next(conn, state)
+		}
+	}
+}
+
+// closeIdle force-closes every tracked connection that is not currently
+// serving a request.
+func (t *connTracker) closeIdle() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for conn, state := range t.conns {
+		if state != http.StateActive {
+			conn.Close()
+		}
+	}
+}
+
+// len reports the number of connections currently tracked.
+func (t *connTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.conns)
+}
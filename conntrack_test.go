@@ -0,0 +1,79 @@
+package cleanuphttp
+
+import (
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeConn is a minimal net.Conn whose Close is observable, so tests can
+// tell whether connTracker actually closed it without the blocking
+// semantics of a real net.Pipe.
+type fakeConn struct {
+	net.Conn
+	closed int32
+}
+
+func (c *fakeConn) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *fakeConn) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) != 0
+}
+
+func TestConnTracker(t *testing.T) {
+	tr := newConnTracker()
+	server := &http.Server{}
+	tr.track(server)
+
+	active := &fakeConn{}
+	idle := &fakeConn{}
+
+	server.ConnState(active, http.StateActive)
+	server.ConnState(idle, http.StateIdle)
+
+	if got := tr.len(); got != 2 {
+		t.Fatalf("len() = %d, want 2", got)
+	}
+
+	tr.closeIdle()
+
+	if active.isClosed() {
+		t.Error("closeIdle closed an active connection")
+	}
+	if !idle.isClosed() {
+		t.Error("closeIdle did not close an idle connection")
+	}
+
+	server.ConnState(idle, http.StateClosed)
+	if got := tr.len(); got != 1 {
+		t.Fatalf("len() after StateClosed = %d, want 1", got)
+	}
+}
+
+// TestConnTrackerChainsExistingConnState verifies track doesn't clobber a
+// ConnState hook that was already set on the server.
+func TestConnTrackerChainsExistingConnState(t *testing.T) {
+	tr := newConnTracker()
+
+	var calledWith http.ConnState
+	server := &http.Server{
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			calledWith = state
+		},
+	}
+	tr.track(server)
+
+	conn := &fakeConn{}
+	server.ConnState(conn, http.StateNew)
+
+	if calledWith != http.StateNew {
+		t.Errorf("chained ConnState got %v, want %v", calledWith, http.StateNew)
+	}
+	if got := tr.len(); got != 1 {
+		t.Fatalf("len() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,108 @@
+package cleanuphttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestShutdownHooksOrder verifies BeforeShutdown, ShutdownInitiated, and
+// ShutdownComplete fire in order relative to the pre/post cleanup stacks.
+func TestShutdownHooksOrder(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	c := &CleanupHTTP{
+		Server:            &http.Server{},
+		NoSignalHandling:  true,
+		BeforeShutdown:    func() { record("before-shutdown") },
+		ShutdownInitiated: func() { record("shutdown-initiated") },
+		ShutdownComplete:  func() { record("shutdown-complete") },
+	}
+	c.PreCleanupPushFunc(func(context.Context) error {
+		record("pre-cleanup")
+		return nil
+	})
+	c.PostCleanupPushFunc(func(context.Context) error {
+		record("post-cleanup")
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(l, time.Second)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	c.Shutdown()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not return after Shutdown")
+	}
+
+	want := []string{"before-shutdown", "pre-cleanup", "shutdown-initiated", "post-cleanup", "shutdown-complete"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+// TestNoSignalHandling verifies that with NoSignalHandling set,
+// ServeListener keeps running until Shutdown is called. No signal is sent.
+func TestNoSignalHandling(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	c := &CleanupHTTP{
+		Server:           &http.Server{},
+		NoSignalHandling: true,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(l, time.Second)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("ServeListener returned on its own (err=%v), want it to keep running until Shutdown", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	c.Shutdown()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not return after Shutdown")
+	}
+}
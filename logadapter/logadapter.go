@@ -0,0 +1,44 @@
+// Package logadapter provides cleanuphttp.Logger adapters for common
+// logging libraries, kept out of the core cleanuphttp package so it stays
+// dependency-free.
+package logadapter
+
+import (
+	"log"
+
+	"github.com/harrison-roh/cleanuphttp"
+)
+
+// PrintfLogger is satisfied by loggers exposing independent Debugf,
+// Infof, Warnf, and Errorf methods, such as *logrus.Logger, a
+// logrus.FieldLogger, or *zap.SugaredLogger. Such loggers already
+// implement cleanuphttp.Logger and can be assigned to CleanupHTTP.Logger
+// directly; NewPrintfLogger exists to give call sites an explicit,
+// documented constructor instead of relying on the structural match.
+type PrintfLogger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NewPrintfLogger adapts l to cleanuphttp.Logger.
+func NewPrintfLogger(l PrintfLogger) cleanuphttp.Logger {
+	return l
+}
+
+// stdLogger adapts a *log.Logger to cleanuphttp.Logger, mapping every
+// level to Printf since the stdlib logger has no levels of its own.
+type stdLogger struct {
+	*log.Logger
+}
+
+// NewStdLogger adapts l to cleanuphttp.Logger.
+func NewStdLogger(l *log.Logger) cleanuphttp.Logger {
+	return stdLogger{Logger: l}
+}
+
+func (l stdLogger) Debugf(format string, args ...interface{}) { l.Printf(format, args...) }
+func (l stdLogger) Infof(format string, args ...interface{})  { l.Printf(format, args...) }
+func (l stdLogger) Warnf(format string, args ...interface{})  { l.Printf(format, args...) }
+func (l stdLogger) Errorf(format string, args ...interface{}) { l.Printf(format, args...) }
@@ -0,0 +1,45 @@
+package logadapter
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0))
+
+	logger.Debugf("debug %d", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Errorf("error %d", 4)
+
+	out := buf.String()
+	for _, want := range []string{"debug 1", "info 2", "warn 3", "error 4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+}
+
+type printfLogger struct {
+	calls []string
+}
+
+func (l *printfLogger) Debugf(format string, args ...interface{}) { l.calls = append(l.calls, "debug") }
+func (l *printfLogger) Infof(format string, args ...interface{})  { l.calls = append(l.calls, "info") }
+func (l *printfLogger) Warnf(format string, args ...interface{})  { l.calls = append(l.calls, "warn") }
+func (l *printfLogger) Errorf(format string, args ...interface{}) { l.calls = append(l.calls, "error") }
+
+func TestNewPrintfLogger(t *testing.T) {
+	p := &printfLogger{}
+	logger := NewPrintfLogger(p)
+
+	logger.Warnf("push failed")
+
+	if len(p.calls) != 1 || p.calls[0] != "warn" {
+		t.Fatalf("calls = %v, want a single warn call", p.calls)
+	}
+}
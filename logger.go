@@ -0,0 +1,41 @@
+package cleanuphttp
+
+import "log"
+
+// Logger is the logging interface used throughout CleanupHTTP. Set it on
+// a per-instance basis via CleanupHTTP.Logger, or replace the fallback
+// used by every CleanupHTTP (including DefaultCleanupHTTP) that doesn't
+// set its own via SetDefaultLogger. Adapters for common logging
+// libraries live in the cleanuphttp/logadapter sub-package, so the core
+// module stays dependency-free.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+var defaultLogger Logger = stdLogger{}
+
+// SetDefaultLogger replaces the fallback Logger used by CleanupHTTP
+// values whose Logger field is unset. The stdlib log package is used
+// until this is called, preserving prior behavior.
+func SetDefaultLogger(logger Logger) {
+	defaultLogger = logger
+}
+
+// logger returns c.Logger, falling back to the default logger.
+func (c *CleanupHTTP) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return defaultLogger
+}
+
+// stdLogger adapts the stdlib log package to Logger.
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...interface{}) { log.Printf(format, args...) }
+func (stdLogger) Infof(format string, args ...interface{})  { log.Printf(format, args...) }
+func (stdLogger) Warnf(format string, args ...interface{})  { log.Printf(format, args...) }
+func (stdLogger) Errorf(format string, args ...interface{}) { log.Printf(format, args...) }
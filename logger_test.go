@@ -0,0 +1,46 @@
+package cleanuphttp
+
+import "testing"
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "debug:"+format)
+}
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, "info:"+format)
+}
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "warn:"+format)
+}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.messages = append(l.messages, "error:"+format)
+}
+
+func TestLoggerPerInstanceOverridesDefault(t *testing.T) {
+	instance := &recordingLogger{}
+	c := &CleanupHTTP{Logger: instance}
+
+	c.logger().Warnf("push failed")
+
+	if len(instance.messages) != 1 || instance.messages[0] != "warn:push failed" {
+		t.Fatalf("instance logger got %v, want a single warn message", instance.messages)
+	}
+}
+
+func TestSetDefaultLogger(t *testing.T) {
+	original := defaultLogger
+	defer func() { defaultLogger = original }()
+
+	fallback := &recordingLogger{}
+	SetDefaultLogger(fallback)
+
+	c := &CleanupHTTP{}
+	c.logger().Infof("using default")
+
+	if len(fallback.messages) != 1 || fallback.messages[0] != "info:using default" {
+		t.Fatalf("default logger got %v, want a single info message", fallback.messages)
+	}
+}
@@ -0,0 +1,86 @@
+package cleanuphttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// readinessCheck is a named check registered via RegisterReadinessCheck.
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// RegisterReadinessCheck adds a named check that /readyz also evaluates
+// while the server is still serving. Once a shutdown signal is received,
+// /readyz fails immediately on that alone and registered checks are no
+// longer consulted, so this only matters before shutdown starts: e.g. a
+// check against a database or upstream dependency that should pull the pod
+// out of rotation on an outage even though the process is otherwise
+// healthy and still accepting connections.
+func (c *CleanupHTTP) RegisterReadinessCheck(name string, check func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readinessChecks = append(c.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// Handler returns the admin http.Handler serving /healthz, /readyz, and
+// /metrics. Serve/ServeListener/ServeTLS mount it on AdminAddr
+// automatically when set; callers who would rather mount it under a path
+// prefix on their own mux can use this directly, e.g.
+// mux.Handle("/admin/", http.StripPrefix("/admin", c.Handler())).
+func (c *CleanupHTTP) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", c.handleHealthz)
+	mux.HandleFunc("/readyz", c.handleReadyz)
+	mux.HandleFunc("/metrics", c.handleMetrics)
+	return mux
+}
+
+// handleHealthz reports liveness: whether the process is up at all,
+// regardless of shutdown or readiness state.
+func (c *CleanupHTTP) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz reports readiness: it fails as soon as shutdown is
+// triggered, and independently whenever a registered readiness check
+// fails, so a k8s endpoints controller stops routing new traffic before
+// Server.Shutdown starts refusing it.
+func (c *CleanupHTTP) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if !c.isReady() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	c.mu.Lock()
+	checks := append([]readinessCheck(nil), c.readinessChecks...)
+	c.mu.Unlock()
+
+	for _, rc := range checks {
+		if err := rc.check(r.Context()); err != nil {
+			http.Error(w, fmt.Sprintf("%s: %s", rc.name, err.Error()), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// handleMetrics reports a minimal set of built-in gauges. Wire up a real
+// metrics library by registering it as a pre-cleanup routine and scraping
+// it independently; this endpoint only covers what CleanupHTTP itself
+// tracks.
+func (c *CleanupHTTP) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	ready := 0
+	if c.isReady() {
+		ready = 1
+	}
+
+	fmt.Fprintf(w, "cleanuphttp_ready %d\n", ready)
+	fmt.Fprintf(w, "cleanuphttp_live_connections %d\n", c.connTracker().len())
+}
@@ -0,0 +1,70 @@
+package cleanuphttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		ready      bool
+		checks     []func(ctx context.Context) error
+		wantStatus int
+	}{
+		{
+			name:       "ready with no checks",
+			ready:      true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "ready with a passing check",
+			ready: true,
+			checks: []func(ctx context.Context) error{
+				func(context.Context) error { return nil },
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "ready with a failing check fails readyz",
+			ready: true,
+			checks: []func(ctx context.Context) error{
+				func(context.Context) error { return errors.New("db unreachable") },
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "not ready short-circuits before checks run",
+			ready: false,
+			checks: []func(ctx context.Context) error{
+				func(context.Context) error {
+					t.Fatal("readiness check ran after shutdown was signaled")
+					return nil
+				},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CleanupHTTP{}
+			c.setReady(tt.ready)
+
+			for i, check := range tt.checks {
+				c.RegisterReadinessCheck(string(rune('a'+i)), check)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			c.handleReadyz(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %q)", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
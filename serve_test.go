@@ -0,0 +1,112 @@
+package cleanuphttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestAddServerSharesLifecycle verifies that a server registered via
+// AddServer is served and shut down alongside Server, under the same
+// cleanup stack.
+func TestAddServerSharesLifecycle(t *testing.T) {
+	mainListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	extraListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	c := &CleanupHTTP{
+		Server:           &http.Server{},
+		NoSignalHandling: true,
+	}
+	if err := c.AddServer(&http.Server{}, extraListener); err != nil {
+		t.Fatalf("AddServer returned unexpected error: %v", err)
+	}
+
+	var cleaned bool
+	c.PostCleanupPushFunc(func(context.Context) error {
+		cleaned = true
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(mainListener, time.Second)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	// The extra server should be reachable while both are serving.
+	conn, err := net.DialTimeout("tcp", extraListener.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("extra server not accepting connections: %v", err)
+	}
+	conn.Close()
+
+	c.Shutdown()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not return after Shutdown")
+	}
+
+	if !cleaned {
+		t.Error("post-cleanup did not run")
+	}
+
+	if _, err := net.DialTimeout("tcp", extraListener.Addr().String(), 200*time.Millisecond); err == nil {
+		t.Error("extra server still accepting connections after shutdown")
+	}
+}
+
+// TestAddServerAfterStartReturnsError verifies that AddServer rejects a
+// late registration instead of silently dropping a server that would
+// never be served, tracked, or shut down.
+func TestAddServerAfterStartReturnsError(t *testing.T) {
+	mainListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	lateListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer lateListener.Close()
+
+	c := &CleanupHTTP{
+		Server:           &http.Server{},
+		NoSignalHandling: true,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(mainListener, time.Second)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	if err := c.AddServer(&http.Server{}, lateListener); err == nil {
+		t.Fatal("AddServer after Serve started returned nil, want an error")
+	}
+
+	c.Shutdown()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not return after Shutdown")
+	}
+}
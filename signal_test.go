@@ -0,0 +1,151 @@
+package cleanuphttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// listenLoopback binds an ephemeral loopback port for a test server to
+// serve on, so tests don't race over a fixed port.
+func listenLoopback(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	return l
+}
+
+// TestConfigurableSignals verifies that CleanupHTTP.Signals overrides the
+// default SIGINT/SIGTERM set.
+func TestConfigurableSignals(t *testing.T) {
+	l := listenLoopback(t)
+
+	c := &CleanupHTTP{
+		Server:  &http.Server{},
+		Signals: []os.Signal{syscall.SIGUSR1},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(l, time.Second)
+	}()
+
+	// Give Serve time to install its signal.Notify before the signal is
+	// sent.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send SIGUSR1: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not shut down after its configured signal")
+	}
+}
+
+// TestReloadSignal verifies that SIGHUP fires the Reload hook instead of
+// shutting the server down, and that a subsequent shutdown signal still
+// works afterwards.
+func TestReloadSignal(t *testing.T) {
+	l := listenLoopback(t)
+
+	reloaded := make(chan struct{}, 1)
+	c := &CleanupHTTP{
+		Server: &http.Server{},
+		Reload: func() { reloaded <- struct{}{} },
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.ServeListener(l, time.Second)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP: %v", err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Reload was not called after SIGHUP")
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("ServeListener returned after SIGHUP (err=%v), want it to keep running", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ServeListener returned unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeListener did not shut down after SIGTERM")
+	}
+}
+
+// TestForceShutdownAfter verifies that a cleanup routine stuck past
+// ForceShutdownAfter is force-killed via os.Exit(1), since that can't be
+// observed from within the same process. It re-execs the test binary to
+// run runForceShutdownAfterHelper and checks its exit code.
+func TestForceShutdownAfter(t *testing.T) {
+	if os.Getenv("CLEANUPHTTP_FORCE_SHUTDOWN_HELPER") == "1" {
+		runForceShutdownAfterHelper()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestForceShutdownAfter")
+	cmd.Env = append(os.Environ(), "CLEANUPHTTP_FORCE_SHUTDOWN_HELPER=1")
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("helper process did not exit with an error: %v", err)
+	}
+	if exitErr.ExitCode() != 1 {
+		t.Fatalf("helper process exited with code %d, want 1", exitErr.ExitCode())
+	}
+}
+
+func runForceShutdownAfterHelper() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.Exit(2)
+	}
+
+	c := &CleanupHTTP{
+		Server:             &http.Server{},
+		ForceShutdownAfter: 50 * time.Millisecond,
+	}
+	c.PreCleanupPushFunc(func(ctx context.Context) error {
+		time.Sleep(time.Hour)
+		return nil
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		c.Shutdown()
+	}()
+
+	c.ServeListener(l, time.Hour)
+}